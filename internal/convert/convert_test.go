@@ -0,0 +1,115 @@
+package convert
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeRateProvider struct {
+	calls int
+	rates map[string]float64
+	err   error
+}
+
+func (f *fakeRateProvider) Get(codes ...string) (map[string]float64, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	out := make(map[string]float64, len(codes))
+	for _, code := range codes {
+		out[code] = f.rates[code]
+	}
+	return out, nil
+}
+
+func TestCachingRateProviderReusesWithinTTL(t *testing.T) {
+	fake := &fakeRateProvider{rates: map[string]float64{"USD": 1, "EUR": 1.1}}
+	cached := NewCachingRateProvider(fake, time.Hour)
+
+	if _, err := cached.Get("USD", "EUR"); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if _, err := cached.Get("EUR", "USD"); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	if fake.calls != 1 {
+		t.Errorf("upstream called %d times, want 1 (second call should hit cache)", fake.calls)
+	}
+}
+
+func TestCachingRateProviderRefetchesAfterTTL(t *testing.T) {
+	fake := &fakeRateProvider{rates: map[string]float64{"USD": 1}}
+	cached := NewCachingRateProvider(fake, -time.Second) // already expired
+
+	if _, err := cached.Get("USD"); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if _, err := cached.Get("USD"); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	if fake.calls != 2 {
+		t.Errorf("upstream called %d times, want 2 (TTL expired each time)", fake.calls)
+	}
+}
+
+func TestCachingRateProviderPropagatesError(t *testing.T) {
+	fake := &fakeRateProvider{err: errors.New("upstream down")}
+	cached := NewCachingRateProvider(fake, time.Hour)
+
+	if _, err := cached.Get("USD"); err == nil {
+		t.Error("Get() error = nil, want upstream error")
+	}
+}
+
+func TestConvertUnitLength(t *testing.T) {
+	got, err := ConvertUnit(1, "km", "m")
+	if err != nil {
+		t.Fatalf("ConvertUnit() error = %v", err)
+	}
+	if got != 1000 {
+		t.Errorf("ConvertUnit(1, km, m) = %v, want 1000", got)
+	}
+}
+
+func TestConvertUnitTemperature(t *testing.T) {
+	got, err := ConvertUnit(0, "c", "f")
+	if err != nil {
+		t.Fatalf("ConvertUnit() error = %v", err)
+	}
+	if got != 32 {
+		t.Errorf("ConvertUnit(0, c, f) = %v, want 32", got)
+	}
+}
+
+func TestConvertUnitMismatchedCategory(t *testing.T) {
+	if _, err := ConvertUnit(1, "kg", "m"); err == nil {
+		t.Error("ConvertUnit(kg, m) expected error for mismatched categories")
+	}
+}
+
+func TestCategoryOf(t *testing.T) {
+	if cat, ok := CategoryOf("KM"); !ok || cat != categoryLength {
+		t.Errorf("CategoryOf(KM) = %q, %v, want %q, true", cat, ok, categoryLength)
+	}
+	if _, ok := CategoryOf("parsec"); ok {
+		t.Error("CategoryOf(parsec) = true, want false for an unknown unit")
+	}
+}
+
+func TestFormatMoney(t *testing.T) {
+	cases := map[float64]string{
+		1234567.8: "1,234,567.80",
+		0:         "0.00",
+		-42.5:     "-42.50",
+		999:       "999.00",
+	}
+	for in, want := range cases {
+		if got := FormatMoney(in); got != want {
+			t.Errorf("FormatMoney(%v) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package convert
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FormatMoney renders v with two decimals and a thousands separator, e.g.
+// 1234567.8 -> "1,234,567.80".
+func FormatMoney(v float64) string {
+	neg := v < 0
+	if neg {
+		v = -v
+	}
+
+	parts := strings.SplitN(fmt.Sprintf("%.2f", v), ".", 2)
+	intPart := parts[0]
+
+	var grouped strings.Builder
+	for i, digit := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+		grouped.WriteRune(digit)
+	}
+
+	sign := ""
+	if neg {
+		sign = "-"
+	}
+	return sign + grouped.String() + "." + parts[1]
+}
@@ -0,0 +1,133 @@
+// Package convert implements currency and unit conversion for the /xr and
+// /conv bot commands.
+package convert
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCacheTTL is how long a fetched set of rates is reused before the
+// provider is asked again.
+const DefaultCacheTTL = time.Hour
+
+// RateProvider resolves currency codes to their value in USD.
+type RateProvider interface {
+	Get(codes ...string) (map[string]float64, error)
+}
+
+// HTTPRateProvider fetches exchange rates from open.er-api.com, which
+// publishes rates relative to a single base currency (USD).
+type HTTPRateProvider struct {
+	client  *http.Client
+	baseURL string
+}
+
+// NewHTTPRateProvider creates a RateProvider backed by open.er-api.com.
+func NewHTTPRateProvider() *HTTPRateProvider {
+	return &HTTPRateProvider{
+		client:  &http.Client{Timeout: 10 * time.Second},
+		baseURL: "https://open.er-api.com/v6/latest/USD",
+	}
+}
+
+type ratesResponse struct {
+	Result string             `json:"result"`
+	Rates  map[string]float64 `json:"rates"`
+}
+
+// Get returns, for each requested currency code, the value of one unit of
+// that currency expressed in USD.
+func (p *HTTPRateProvider) Get(codes ...string) (map[string]float64, error) {
+	resp, err := p.client.Get(p.baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("запрос курсов валют: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("провайдер курсов валют вернул статус %d", resp.StatusCode)
+	}
+
+	var payload ratesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("разбор ответа провайдера курсов валют: %w", err)
+	}
+	if payload.Result != "success" {
+		return nil, fmt.Errorf("провайдер курсов валют вернул ошибку")
+	}
+
+	out := make(map[string]float64, len(codes))
+	for _, code := range codes {
+		code = strings.ToUpper(code)
+		perUSD, ok := payload.Rates[code]
+		if !ok || perUSD == 0 {
+			return nil, fmt.Errorf("неизвестный код валюты: %s", code)
+		}
+		out[code] = 1 / perUSD
+	}
+	return out, nil
+}
+
+// cachedRates is a single cache entry: the resolved rates and when they
+// were fetched.
+type cachedRates struct {
+	rates     map[string]float64
+	fetchedAt time.Time
+}
+
+// CachingRateProvider wraps a RateProvider with an in-memory TTL cache
+// keyed by the sorted set of requested currency codes.
+type CachingRateProvider struct {
+	mu       sync.Mutex
+	upstream RateProvider
+	ttl      time.Duration
+	cache    map[string]cachedRates
+}
+
+// NewCachingRateProvider wraps upstream with a cache of the given TTL.
+func NewCachingRateProvider(upstream RateProvider, ttl time.Duration) *CachingRateProvider {
+	return &CachingRateProvider{
+		upstream: upstream,
+		ttl:      ttl,
+		cache:    make(map[string]cachedRates),
+	}
+}
+
+func cacheKey(codes []string) string {
+	sorted := append([]string(nil), codes...)
+	for i, code := range sorted {
+		sorted[i] = strings.ToUpper(code)
+	}
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+// Get returns cached rates for codes if they were fetched within the TTL,
+// otherwise it fetches and caches a fresh set.
+func (c *CachingRateProvider) Get(codes ...string) (map[string]float64, error) {
+	key := cacheKey(codes)
+
+	c.mu.Lock()
+	if cached, ok := c.cache[key]; ok && time.Since(cached.fetchedAt) < c.ttl {
+		c.mu.Unlock()
+		return cached.rates, nil
+	}
+	c.mu.Unlock()
+
+	rates, err := c.upstream.Get(codes...)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.cache[key] = cachedRates{rates: rates, fetchedAt: time.Now()}
+	c.mu.Unlock()
+
+	return rates, nil
+}
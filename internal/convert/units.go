@@ -0,0 +1,96 @@
+package convert
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	categoryLength      = "length"
+	categoryMass        = "mass"
+	categoryTemperature = "temperature"
+)
+
+// unit describes a unit's physical quantity and, for length/mass units, its
+// factor to the category's SI base unit (meter, kilogram). Temperature
+// units are affine rather than linear, so they're converted separately.
+type unit struct {
+	category string
+	toBase   float64
+}
+
+// units is the static table of supported length, mass and temperature
+// units, keyed by their lowercase symbol.
+var units = map[string]unit{
+	"m":  {category: categoryLength, toBase: 1},
+	"km": {category: categoryLength, toBase: 1000},
+	"cm": {category: categoryLength, toBase: 0.01},
+	"mm": {category: categoryLength, toBase: 0.001},
+	"mi": {category: categoryLength, toBase: 1609.344},
+	"yd": {category: categoryLength, toBase: 0.9144},
+	"ft": {category: categoryLength, toBase: 0.3048},
+	"in": {category: categoryLength, toBase: 0.0254},
+
+	"kg": {category: categoryMass, toBase: 1},
+	"g":  {category: categoryMass, toBase: 0.001},
+	"mg": {category: categoryMass, toBase: 0.000001},
+	"lb": {category: categoryMass, toBase: 0.45359237},
+	"oz": {category: categoryMass, toBase: 0.028349523125},
+
+	"c": {category: categoryTemperature},
+	"f": {category: categoryTemperature},
+	"k": {category: categoryTemperature},
+}
+
+// CategoryOf returns the physical quantity of unit u (e.g. "length"), and
+// whether u is recognised at all.
+func CategoryOf(u string) (string, bool) {
+	unit, ok := units[strings.ToLower(u)]
+	return unit.category, ok
+}
+
+// ConvertUnit converts amount from one unit to another. from and to must be
+// units of the same physical quantity.
+func ConvertUnit(amount float64, from, to string) (float64, error) {
+	from = strings.ToLower(from)
+	to = strings.ToLower(to)
+
+	uf, ok := units[from]
+	if !ok {
+		return 0, fmt.Errorf("неизвестная единица измерения: %s", from)
+	}
+	ut, ok := units[to]
+	if !ok {
+		return 0, fmt.Errorf("неизвестная единица измерения: %s", to)
+	}
+	if uf.category != ut.category {
+		return 0, fmt.Errorf("нельзя преобразовать %s в %s: разные величины", from, to)
+	}
+
+	if uf.category == categoryTemperature {
+		return convertTemperature(amount, from, to)
+	}
+	return amount * uf.toBase / ut.toBase, nil
+}
+
+func convertTemperature(amount float64, from, to string) (float64, error) {
+	var celsius float64
+	switch from {
+	case "c":
+		celsius = amount
+	case "f":
+		celsius = (amount - 32) * 5 / 9
+	case "k":
+		celsius = amount - 273.15
+	}
+
+	switch to {
+	case "c":
+		return celsius, nil
+	case "f":
+		return celsius*9/5 + 32, nil
+	case "k":
+		return celsius + 273.15, nil
+	}
+	return 0, fmt.Errorf("неизвестная единица измерения: %s", to)
+}
@@ -0,0 +1,345 @@
+// Package expr implements a shunting-yard based parser and evaluator for
+// arithmetic expressions with parentheses, unary operators, named constants
+// and functions (e.g. "2*(3+4)^2", "-sin(0.5)+sqrt(2)", "log(100)/log(10)").
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// precedence and associativity of binary operators. Unary minus/plus use a
+// precedence of 4 and are right-associative; "^" and "**" are also
+// right-associative.
+var precedence = map[string]int{
+	"+": 2, "-": 2,
+	"*": 3, "/": 3, "%": 3, "×": 3, "÷": 3,
+	"^": 5, "**": 5,
+}
+
+func isRightAssoc(op string) bool {
+	return op == "^" || op == "**"
+}
+
+// Tokenize turns expr (with spaces already allowed) into a token stream.
+func Tokenize(s string) ([]Token, error) {
+	var tokens []Token
+	runes := []rune(s)
+	i := 0
+	for i < len(runes) {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case unicode.IsDigit(r) || r == '.':
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			text := string(runes[start:i])
+			num, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				return nil, fmt.Errorf("некорректное число: %s", text)
+			}
+			tokens = append(tokens, Token{Kind: TokNumber, Text: text, Num: num})
+
+		case unicode.IsLetter(r):
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			name := string(runes[start:i])
+			j := i
+			for j < len(runes) && unicode.IsSpace(runes[j]) {
+				j++
+			}
+			if j < len(runes) && runes[j] == '(' {
+				tokens = append(tokens, Token{Kind: TokFunction, Text: strings.ToLower(name)})
+			} else {
+				tokens = append(tokens, Token{Kind: TokIdent, Text: strings.ToLower(name)})
+			}
+
+		case r == '(':
+			tokens = append(tokens, Token{Kind: TokLParen, Text: "("})
+			i++
+
+		case r == ')':
+			tokens = append(tokens, Token{Kind: TokRParen, Text: ")"})
+			i++
+
+		case r == ',':
+			tokens = append(tokens, Token{Kind: TokComma, Text: ","})
+			i++
+
+		case r == '!':
+			tokens = append(tokens, Token{Kind: TokOperator, Text: "!"})
+			i++
+
+		case r == '*' && i+1 < len(runes) && runes[i+1] == '*':
+			tokens = append(tokens, Token{Kind: TokOperator, Text: "**"})
+			i += 2
+
+		case strings.ContainsRune("+-*/×÷^%", r):
+			tokens = append(tokens, Token{Kind: TokOperator, Text: string(r)})
+			i++
+
+		default:
+			return nil, fmt.Errorf("недопустимый символ: %q", r)
+		}
+	}
+	return tokens, nil
+}
+
+// isUnaryContext reports whether a '+'/'-' token at this position should be
+// treated as a unary operator: expression start, or right after another
+// operator, an opening paren, or a comma.
+func isUnaryContext(prev *Token) bool {
+	if prev == nil {
+		return true
+	}
+	switch prev.Kind {
+	case TokOperator, TokUnaryMinus, TokLParen, TokComma:
+		return true
+	default:
+		return false
+	}
+}
+
+// ToRPN converts infix tokens to reverse-polish notation using the
+// shunting-yard algorithm, with a function-call stack that pops arguments on
+// comma and closing paren.
+func ToRPN(tokens []Token) ([]Token, error) {
+	var output []Token
+	var ops []Token
+	var argCounts []int
+
+	var prev *Token
+	for idx := range tokens {
+		t := tokens[idx]
+
+		switch t.Kind {
+		case TokNumber:
+			output = append(output, t)
+
+		case TokIdent:
+			val, ok := Constants[t.Text]
+			if !ok {
+				return nil, fmt.Errorf("неизвестный идентификатор: %s", t.Text)
+			}
+			output = append(output, Token{Kind: TokNumber, Num: val})
+
+		case TokFunction:
+			ops = append(ops, t)
+			argCounts = append(argCounts, 0)
+
+		case TokComma:
+			if len(ops) == 0 {
+				return nil, fmt.Errorf("запятая вне вызова функции")
+			}
+			for len(ops) > 0 && ops[len(ops)-1].Kind != TokLParen {
+				output = append(output, ops[len(ops)-1])
+				ops = ops[:len(ops)-1]
+			}
+			if len(ops) == 0 {
+				return nil, fmt.Errorf("несогласованные скобки")
+			}
+			// A comma is only valid directly inside a function call, i.e.
+			// the '(' it landed on must itself sit right on top of the
+			// TokFunction that opened it; a bare grouping "(" never pushed
+			// an argCounts entry to increment.
+			if len(ops) < 2 || ops[len(ops)-2].Kind != TokFunction {
+				return nil, fmt.Errorf("запятая вне вызова функции")
+			}
+			argCounts[len(argCounts)-1]++
+
+		case TokOperator:
+			if t.Text == "!" {
+				output = append(output, t)
+				prev = &tokens[idx]
+				continue
+			}
+
+			if (t.Text == "-" || t.Text == "+") && isUnaryContext(prev) {
+				// A unary operator has not consumed an operand yet, so nothing
+				// on the stack can be reduced against it; push it like an
+				// opening paren and let later operators pop it via opPrecedence.
+				ops = append(ops, Token{Kind: TokUnaryMinus, Text: t.Text})
+				prev = &tokens[idx]
+				continue
+			}
+
+			op := t
+			for len(ops) > 0 {
+				top := ops[len(ops)-1]
+				if top.Kind != TokOperator && top.Kind != TokUnaryMinus {
+					break
+				}
+				topPrec := opPrecedence(top)
+				curPrec := opPrecedence(op)
+				if topPrec > curPrec || (topPrec == curPrec && !isRightAssoc(op.Text)) {
+					output = append(output, top)
+					ops = ops[:len(ops)-1]
+					continue
+				}
+				break
+			}
+			ops = append(ops, op)
+
+		case TokLParen:
+			ops = append(ops, t)
+
+		case TokRParen:
+			for len(ops) > 0 && ops[len(ops)-1].Kind != TokLParen {
+				output = append(output, ops[len(ops)-1])
+				ops = ops[:len(ops)-1]
+			}
+			if len(ops) == 0 {
+				return nil, fmt.Errorf("несогласованные скобки")
+			}
+			ops = ops[:len(ops)-1] // pop the matching '('
+
+			if len(ops) > 0 && ops[len(ops)-1].Kind == TokFunction {
+				fn := ops[len(ops)-1]
+				ops = ops[:len(ops)-1]
+				argc := argCounts[len(argCounts)-1]
+				argCounts = argCounts[:len(argCounts)-1]
+				emptyCall := prev != nil && prev.Kind == TokLParen
+				if !emptyCall {
+					argc++
+				}
+				output = append(output, Token{Kind: TokFunction, Text: fn.Text, Num: float64(argc)})
+			}
+		}
+
+		prev = &tokens[idx]
+	}
+
+	for len(ops) > 0 {
+		top := ops[len(ops)-1]
+		if top.Kind == TokLParen || top.Kind == TokFunction {
+			return nil, fmt.Errorf("несогласованные скобки")
+		}
+		output = append(output, top)
+		ops = ops[:len(ops)-1]
+	}
+
+	return output, nil
+}
+
+func opPrecedence(t Token) int {
+	if t.Kind == TokUnaryMinus {
+		return 4
+	}
+	return precedence[t.Text]
+}
+
+// Eval evaluates an RPN token stream, using binOps for binary operators
+// (e.g. the table built by Calculator.operators) and the package-level Functions table for
+// named functions.
+func Eval(rpn []Token, binOps map[string]func(a, b float64) (float64, error)) (float64, error) {
+	var stack []float64
+
+	pop := func() (float64, error) {
+		if len(stack) == 0 {
+			return 0, fmt.Errorf("неправильный формат выражения")
+		}
+		v := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		return v, nil
+	}
+
+	for _, t := range rpn {
+		switch t.Kind {
+		case TokNumber:
+			stack = append(stack, t.Num)
+
+		case TokUnaryMinus:
+			a, err := pop()
+			if err != nil {
+				return 0, err
+			}
+			if t.Text == "-" {
+				stack = append(stack, -a)
+			} else {
+				stack = append(stack, a)
+			}
+
+		case TokOperator:
+			if t.Text == "!" {
+				a, err := pop()
+				if err != nil {
+					return 0, err
+				}
+				v, err := factorial(a)
+				if err != nil {
+					return 0, err
+				}
+				stack = append(stack, v)
+				continue
+			}
+
+			b, err := pop()
+			if err != nil {
+				return 0, err
+			}
+			a, err := pop()
+			if err != nil {
+				return 0, err
+			}
+			opFunc, ok := binOps[t.Text]
+			if !ok {
+				return 0, fmt.Errorf("неподдерживаемая операция: %s", t.Text)
+			}
+			v, err := opFunc(a, b)
+			if err != nil {
+				return 0, err
+			}
+			stack = append(stack, v)
+
+		case TokFunction:
+			argc := int(t.Num)
+			fn, ok := Functions[t.Text]
+			if !ok {
+				return 0, fmt.Errorf("неизвестная функция: %s", t.Text)
+			}
+			if len(stack) < argc {
+				return 0, fmt.Errorf("неправильный формат выражения")
+			}
+			args := make([]float64, argc)
+			copy(args, stack[len(stack)-argc:])
+			stack = stack[:len(stack)-argc]
+			if err := fn.CheckArity(t.Text, argc); err != nil {
+				return 0, err
+			}
+			v, err := fn.Call(args)
+			if err != nil {
+				return 0, err
+			}
+			stack = append(stack, v)
+
+		default:
+			return 0, fmt.Errorf("неправильный формат выражения")
+		}
+	}
+
+	if len(stack) != 1 {
+		return 0, fmt.Errorf("неправильный формат выражения")
+	}
+	return stack[0], nil
+}
+
+func factorial(a float64) (float64, error) {
+	if a < 0 || a != float64(int64(a)) {
+		return 0, fmt.Errorf("факториал определён только для неотрицательных целых чисел")
+	}
+	n := int64(a)
+	result := 1.0
+	for i := int64(2); i <= n; i++ {
+		result *= float64(i)
+	}
+	return result, nil
+}
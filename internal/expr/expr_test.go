@@ -0,0 +1,134 @@
+package expr
+
+import (
+	"math"
+	"testing"
+)
+
+func testBinOps() map[string]func(a, b float64) (float64, error) {
+	return map[string]func(a, b float64) (float64, error){
+		"+": func(a, b float64) (float64, error) { return a + b, nil },
+		"-": func(a, b float64) (float64, error) { return a - b, nil },
+		"*": func(a, b float64) (float64, error) { return a * b, nil },
+		"/": func(a, b float64) (float64, error) { return a / b, nil },
+		"%": func(a, b float64) (float64, error) { return math.Mod(a, b), nil },
+		"^": func(a, b float64) (float64, error) { return math.Pow(a, b), nil },
+	}
+}
+
+func eval(t *testing.T, s string) (float64, error) {
+	t.Helper()
+	tokens, err := Tokenize(s)
+	if err != nil {
+		return 0, err
+	}
+	rpn, err := ToRPN(tokens)
+	if err != nil {
+		return 0, err
+	}
+	return Eval(rpn, testBinOps())
+}
+
+func TestEvalPrecedenceAndAssociativity(t *testing.T) {
+	cases := []struct {
+		expr string
+		want float64
+	}{
+		{"2+3*4", 14},
+		{"(2+3)*4", 20},
+		{"2^3^2", 512},   // right-associative: 2^(3^2)
+		{"2^-2", 0.25},   // unary binds tighter than a pending '^' pop
+		{"2^-2+1", 1.25},
+		{"-2^2", -4},     // unary minus is lower precedence than '^'
+		{"3-2-1", 0},     // left-associative '-'
+		{"-3!", -6},
+		{"2*-3", -6},
+	}
+	for _, c := range cases {
+		got, err := eval(t, c.expr)
+		if err != nil {
+			t.Errorf("eval(%q) error = %v, want %v", c.expr, err, c.want)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("eval(%q) = %v, want %v", c.expr, got, c.want)
+		}
+	}
+}
+
+func TestEvalFunctionsAndConstants(t *testing.T) {
+	cases := []struct {
+		expr string
+		want float64
+	}{
+		{"sqrt(16)", 4},
+		{"max(1,5,3)", 5},
+		{"min(1,5,3)", 1},
+		{"pow(2,10)", 1024},
+		{"round(pi)", 3},
+	}
+	for _, c := range cases {
+		got, err := eval(t, c.expr)
+		if err != nil {
+			t.Errorf("eval(%q) error = %v, want %v", c.expr, err, c.want)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("eval(%q) = %v, want %v", c.expr, got, c.want)
+		}
+	}
+}
+
+func TestEvalFactorial(t *testing.T) {
+	if got, err := eval(t, "5!"); err != nil || got != 120 {
+		t.Errorf("eval(5!) = %v, %v, want 120, nil", got, err)
+	}
+	if got, err := eval(t, "0!"); err != nil || got != 1 {
+		t.Errorf("eval(0!) = %v, %v, want 1, nil", got, err)
+	}
+	if _, err := eval(t, "(-1)!"); err == nil {
+		t.Error("eval((-1)!) expected error for negative operand")
+	}
+	if _, err := eval(t, "(1.5)!"); err == nil {
+		t.Error("eval((1.5)!) expected error for non-integer operand")
+	}
+}
+
+func TestToRPNMismatchedParens(t *testing.T) {
+	cases := []string{"(1+2", "1+2)", "(1+(2)"}
+	for _, c := range cases {
+		if _, err := eval(t, c); err == nil {
+			t.Errorf("eval(%q) expected mismatched-parens error", c)
+		}
+	}
+}
+
+func TestToRPNCommaOutsideFunctionCall(t *testing.T) {
+	cases := []string{"(1,2)", "(1,2,3)", "((1,2))", "(sin(1),2)"}
+	for _, c := range cases {
+		if _, err := eval(t, c); err == nil {
+			t.Errorf("eval(%q) expected comma-outside-function-call error", c)
+		}
+	}
+}
+
+func TestToRPNUnknownIdentifier(t *testing.T) {
+	if _, err := eval(t, "foo+1"); err == nil {
+		t.Error("eval(foo+1) expected unknown-identifier error")
+	}
+}
+
+func TestEvalUnknownFunction(t *testing.T) {
+	if _, err := eval(t, "bogus(1)"); err == nil {
+		t.Error("eval(bogus(1)) expected unknown-function error")
+	}
+}
+
+func TestEvalWrongArity(t *testing.T) {
+	cases := []string{"sqrt(1,2)", "pow(1)", "min()"}
+	for _, c := range cases {
+		if _, err := eval(t, c); err == nil {
+			t.Errorf("eval(%q) expected arity error", c)
+		}
+	}
+}
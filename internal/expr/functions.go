@@ -0,0 +1,111 @@
+package expr
+
+import (
+	"fmt"
+	"math"
+)
+
+// Function is a named, callable function usable inside an expression, such
+// as sin(x) or max(a, b, c). MinArity/MaxArity bound the number of arguments
+// accepted; MaxArity of -1 means the function is variadic.
+type Function struct {
+	MinArity int
+	MaxArity int
+	Call     func(args []float64) (float64, error)
+}
+
+// Constants holds the named constants that ToRPN resolves identifiers
+// against, such as pi and e, keyed by their lowercase identifier.
+var Constants = map[string]float64{
+	"pi": math.Pi,
+	"e":  math.E,
+}
+
+func fixed1(f func(float64) float64) Function {
+	return Function{
+		MinArity: 1,
+		MaxArity: 1,
+		Call: func(args []float64) (float64, error) {
+			return f(args[0]), nil
+		},
+	}
+}
+
+// Functions holds every function callable from an expression, keyed by its
+// lowercase name.
+var Functions = map[string]Function{
+	"sin":   fixed1(math.Sin),
+	"cos":   fixed1(math.Cos),
+	"tan":   fixed1(math.Tan),
+	"asin":  fixed1(math.Asin),
+	"acos":  fixed1(math.Acos),
+	"atan":  fixed1(math.Atan),
+	"abs":   fixed1(math.Abs),
+	"floor": fixed1(math.Floor),
+	"ceil":  fixed1(math.Ceil),
+	"round": fixed1(math.Round),
+	"ln":    fixed1(math.Log),
+	"log2":  fixed1(math.Log2),
+	"log": {
+		MinArity: 1,
+		MaxArity: 1,
+		Call: func(args []float64) (float64, error) {
+			return math.Log10(args[0]), nil
+		},
+	},
+	"sqrt": {
+		MinArity: 1,
+		MaxArity: 1,
+		Call: func(args []float64) (float64, error) {
+			if args[0] < 0 {
+				return 0, fmt.Errorf("извлечение корня из отрицательного числа")
+			}
+			return math.Sqrt(args[0]), nil
+		},
+	},
+	"pow": {
+		MinArity: 2,
+		MaxArity: 2,
+		Call: func(args []float64) (float64, error) {
+			return math.Pow(args[0], args[1]), nil
+		},
+	},
+	"min": {
+		MinArity: 1,
+		MaxArity: -1,
+		Call: func(args []float64) (float64, error) {
+			m := args[0]
+			for _, v := range args[1:] {
+				if v < m {
+					m = v
+				}
+			}
+			return m, nil
+		},
+	},
+	"max": {
+		MinArity: 1,
+		MaxArity: -1,
+		Call: func(args []float64) (float64, error) {
+			m := args[0]
+			for _, v := range args[1:] {
+				if v > m {
+					m = v
+				}
+			}
+			return m, nil
+		},
+	},
+}
+
+// CheckArity reports an error in Russian if argc does not satisfy the
+// function's declared arity.
+func (f Function) CheckArity(name string, argc int) error {
+	if argc < f.MinArity || (f.MaxArity != -1 && argc > f.MaxArity) {
+		if f.MinArity == f.MaxArity {
+			return fmt.Errorf("функция %s ожидает %d аргумент(ов), получено %d", name, f.MinArity, argc)
+		}
+		return fmt.Errorf("функция %s ожидает от %d аргумент(ов), получено %d", name, f.MinArity, argc)
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package expr
+
+// TokenKind describes the lexical category of a Token produced by Tokenize.
+type TokenKind int
+
+const (
+	TokNumber TokenKind = iota
+	TokIdent
+	TokOperator
+	TokUnaryMinus
+	TokLParen
+	TokRParen
+	TokComma
+	TokFunction
+)
+
+// Token is a single lexical unit of an expression.
+type Token struct {
+	Kind TokenKind
+	Text string
+	Num  float64
+}
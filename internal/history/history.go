@@ -0,0 +1,180 @@
+// Package history keeps a bounded per-chat log of calculator results so
+// users can recall them later via /history, $N, and the ans keyword.
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"sync"
+)
+
+// MaxEntries is how many entries are kept per chat; older entries are
+// evicted once this limit is exceeded.
+const MaxEntries = 50
+
+// Entry is a single recorded calculation.
+type Entry struct {
+	Expr   string `json:"expr"`
+	Result string `json:"result"`
+}
+
+// History stores a ring buffer of Entry per chat ID plus each chat's
+// language preference, optionally persisted to a JSON file on disk.
+type History struct {
+	mu      sync.RWMutex
+	path    string
+	entries map[int64][]Entry
+	langs   map[int64]string
+}
+
+// fileData is the on-disk JSON representation of a History.
+type fileData struct {
+	Entries map[int64][]Entry `json:"entries"`
+	Langs   map[int64]string  `json:"langs"`
+}
+
+// New creates a History persisted to path. If path is empty, the history is
+// kept in memory only. If the file already exists, its contents are loaded.
+func New(path string) (*History, error) {
+	h := &History{
+		path:    path,
+		entries: make(map[int64][]Entry),
+		langs:   make(map[int64]string),
+	}
+
+	if path == "" {
+		return h, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return h, nil
+		}
+		return nil, fmt.Errorf("чтение файла истории: %w", err)
+	}
+	if len(data) == 0 {
+		return h, nil
+	}
+
+	var stored fileData
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("разбор файла истории: %w", err)
+	}
+	if stored.Entries != nil {
+		h.entries = stored.Entries
+	}
+	if stored.Langs != nil {
+		h.langs = stored.Langs
+	}
+
+	return h, nil
+}
+
+// save persists the current state to disk. Callers must hold h.mu.
+func (h *History) save() error {
+	if h.path == "" {
+		return nil
+	}
+	data, err := json.Marshal(fileData{Entries: h.entries, Langs: h.langs})
+	if err != nil {
+		return fmt.Errorf("сериализация истории: %w", err)
+	}
+	if err := os.WriteFile(h.path, data, 0o644); err != nil {
+		return fmt.Errorf("запись файла истории: %w", err)
+	}
+	return nil
+}
+
+// Lang returns chatID's saved language preference, or "" if none is set.
+func (h *History) Lang(chatID int64) string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.langs[chatID]
+}
+
+// SetLang saves chatID's language preference.
+func (h *History) SetLang(chatID int64, lang string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.langs[chatID] = lang
+	return h.save()
+}
+
+// Add appends a new entry for chatID, evicting the oldest entry once
+// MaxEntries is exceeded.
+func (h *History) Add(chatID int64, expr, result string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	entries := append(h.entries[chatID], Entry{Expr: expr, Result: result})
+	if len(entries) > MaxEntries {
+		entries = entries[len(entries)-MaxEntries:]
+	}
+	h.entries[chatID] = entries
+
+	return h.save()
+}
+
+// List returns a copy of chatID's history, oldest first.
+func (h *History) List(chatID int64) []Entry {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	entries := h.entries[chatID]
+	out := make([]Entry, len(entries))
+	copy(out, entries)
+	return out
+}
+
+// Clear wipes chatID's history.
+func (h *History) Clear(chatID int64) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.entries, chatID)
+	return h.save()
+}
+
+var (
+	ansRe = regexp.MustCompile(`\bans\b`)
+	nthRe = regexp.MustCompile(`\$(\d+)`)
+)
+
+// Substitute replaces the "ans" keyword with chatID's last result and "$N"
+// with the result of its Nth (1-indexed) entry, returning an error if there
+// is no matching history.
+func (h *History) Substitute(chatID int64, expr string) (string, error) {
+	h.mu.RLock()
+	entries := h.entries[chatID]
+	h.mu.RUnlock()
+
+	var substErr error
+	expr = nthRe.ReplaceAllStringFunc(expr, func(match string) string {
+		if substErr != nil {
+			return match
+		}
+		n, _ := strconv.Atoi(nthRe.FindStringSubmatch(match)[1])
+		if n < 1 || n > len(entries) {
+			substErr = fmt.Errorf("запись истории $%d не найдена", n)
+			return match
+		}
+		return entries[n-1].Result
+	})
+	if substErr != nil {
+		return "", substErr
+	}
+
+	if ansRe.MatchString(expr) {
+		if len(entries) == 0 {
+			return "", fmt.Errorf("история пуста, ans недоступен")
+		}
+		expr = ansRe.ReplaceAllString(expr, entries[len(entries)-1].Result)
+	}
+
+	return expr, nil
+}
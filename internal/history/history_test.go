@@ -0,0 +1,108 @@
+package history
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestAddEviction(t *testing.T) {
+	h, err := New("")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	for i := 0; i < MaxEntries+10; i++ {
+		if err := h.Add(1, "1+1", "2"); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+
+	entries := h.List(1)
+	if len(entries) != MaxEntries {
+		t.Fatalf("len(entries) = %d, want %d", len(entries), MaxEntries)
+	}
+}
+
+func TestSubstitute(t *testing.T) {
+	h, err := New("")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if err := h.Add(1, "2+2", "4"); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if err := h.Add(1, "10/2", "5"); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	got, err := h.Substitute(1, "ans*2")
+	if err != nil {
+		t.Fatalf("Substitute() error = %v", err)
+	}
+	if got != "5*2" {
+		t.Errorf("Substitute(ans*2) = %q, want %q", got, "5*2")
+	}
+
+	got, err = h.Substitute(1, "$1+$2")
+	if err != nil {
+		t.Fatalf("Substitute() error = %v", err)
+	}
+	if got != "4+5" {
+		t.Errorf("Substitute($1+$2) = %q, want %q", got, "4+5")
+	}
+
+	if _, err := h.Substitute(1, "$9"); err == nil {
+		t.Error("Substitute($9) expected error for out-of-range index")
+	}
+
+	if _, err := h.Substitute(2, "ans"); err == nil {
+		t.Error("Substitute(ans) expected error for empty history")
+	}
+}
+
+func TestClear(t *testing.T) {
+	h, err := New("")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if err := h.Add(1, "1+1", "2"); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if err := h.Clear(1); err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	if entries := h.List(1); len(entries) != 0 {
+		t.Errorf("List() after Clear() = %v, want empty", entries)
+	}
+}
+
+func TestPersistenceRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+
+	h1, err := New(path)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := h1.Add(42, "2^10", "1024"); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if err := h1.SetLang(42, "en"); err != nil {
+		t.Fatalf("SetLang() error = %v", err)
+	}
+
+	h2, err := New(path)
+	if err != nil {
+		t.Fatalf("New() (reload) error = %v", err)
+	}
+
+	if lang := h2.Lang(42); lang != "en" {
+		t.Errorf("Lang() after reload = %q, want %q", lang, "en")
+	}
+
+	entries := h2.List(42)
+	if len(entries) != 1 || entries[0].Expr != "2^10" || entries[0].Result != "1024" {
+		t.Errorf("List() after reload = %v, want single entry {2^10 1024}", entries)
+	}
+}
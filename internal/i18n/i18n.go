@@ -0,0 +1,84 @@
+// Package i18n provides per-chat message translation for the bot's
+// user-facing strings, loaded from embedded locale files.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+//go:embed locales/*.json
+var localesFS embed.FS
+
+// DefaultLang is used whenever a chat has no language preference set, and
+// as the fallback when a key is missing from the requested language.
+const DefaultLang = "ru"
+
+// Translator holds the loaded translations for every supported language.
+type Translator struct {
+	messages map[string]map[string]string
+}
+
+// New loads every locales/*.json file embedded in the binary.
+func New() (*Translator, error) {
+	entries, err := localesFS.ReadDir("locales")
+	if err != nil {
+		return nil, fmt.Errorf("чтение каталога локализаций: %w", err)
+	}
+
+	t := &Translator{messages: make(map[string]map[string]string, len(entries))}
+	for _, entry := range entries {
+		lang := strings.TrimSuffix(entry.Name(), ".json")
+
+		data, err := localesFS.ReadFile("locales/" + entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("чтение локализации %s: %w", lang, err)
+		}
+
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return nil, fmt.Errorf("разбор локализации %s: %w", lang, err)
+		}
+		t.messages[lang] = messages
+	}
+
+	return t, nil
+}
+
+// Supported reports whether lang has its own translation table.
+func (t *Translator) Supported(lang string) bool {
+	_, ok := t.messages[lang]
+	return ok
+}
+
+func (t *Translator) lookup(lang, key string) string {
+	if messages, ok := t.messages[lang]; ok {
+		if s, ok := messages[key]; ok {
+			return s
+		}
+	}
+	if messages, ok := t.messages[DefaultLang]; ok {
+		if s, ok := messages[key]; ok {
+			return s
+		}
+	}
+	return key
+}
+
+// T returns the translation of key for lang, formatting it with args if any
+// are given. Falls back to DefaultLang, then to the key itself.
+func (t *Translator) T(lang, key string, args ...interface{}) string {
+	message := t.lookup(lang, key)
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// Errorf is like T but returns the translated message as an error.
+func (t *Translator) Errorf(lang, key string, args ...interface{}) error {
+	return errors.New(t.T(lang, key, args...))
+}
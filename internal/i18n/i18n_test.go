@@ -0,0 +1,34 @@
+package i18n
+
+import "testing"
+
+func TestTFallsBackToDefaultLang(t *testing.T) {
+	translator, err := New()
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if got := translator.T("fr", "div_by_zero"); got != translator.T(DefaultLang, "div_by_zero") {
+		t.Errorf("T(fr, div_by_zero) = %q, want fallback to %s", got, DefaultLang)
+	}
+
+	if got := translator.T("en", "expr_too_long", 100); got == "" {
+		t.Error("T(en, expr_too_long, 100) returned empty string")
+	}
+}
+
+func TestSupported(t *testing.T) {
+	translator, err := New()
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	for _, lang := range []string{"ru", "en"} {
+		if !translator.Supported(lang) {
+			t.Errorf("Supported(%q) = false, want true", lang)
+		}
+	}
+	if translator.Supported("xx") {
+		t.Error("Supported(xx) = true, want false")
+	}
+}
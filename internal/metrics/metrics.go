@@ -0,0 +1,32 @@
+// Package metrics defines the bot's Prometheus instrumentation.
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// Metrics holds the bot's Prometheus collectors.
+type Metrics struct {
+	RequestsTotal   *prometheus.CounterVec
+	Duration        prometheus.Histogram
+	UpdatesInFlight prometheus.Gauge
+}
+
+// New creates and registers the bot's collectors against reg.
+func New(reg prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		RequestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "calc_requests_total",
+			Help: "Total number of calculation requests, by outcome.",
+		}, []string{"outcome"}),
+		Duration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name: "calc_duration_seconds",
+			Help: "Time spent handling a calculation request.",
+		}),
+		UpdatesInFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "telegram_updates_in_flight",
+			Help: "Number of Telegram updates currently being processed.",
+		}),
+	}
+
+	reg.MustRegister(m.RequestsTotal, m.Duration, m.UpdatesInFlight)
+	return m
+}
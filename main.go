@@ -3,17 +3,27 @@ package main
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"math"
+	"net/http"
 	"os"
 	"os/signal"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
+	"github.com/MederX/calculator-telegram-bot/internal/convert"
+	"github.com/MederX/calculator-telegram-bot/internal/expr"
+	"github.com/MederX/calculator-telegram-bot/internal/history"
+	"github.com/MederX/calculator-telegram-bot/internal/i18n"
+	"github.com/MederX/calculator-telegram-bot/internal/metrics"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 const (
@@ -22,122 +32,85 @@ const (
 )
 
 type Calculator struct {
-	supportedOps map[string]func(float64, float64) (float64, error)
+	translator *i18n.Translator
 }
 
-func NewCalculator() *Calculator {
-	return &Calculator{
-		supportedOps: map[string]func(float64, float64) (float64, error){
-			"+": func(a, b float64) (float64, error) { return a + b, nil },
-			"-": func(a, b float64) (float64, error) { return a - b, nil },
-			"*": func(a, b float64) (float64, error) { return a * b, nil },
-			"×": func(a, b float64) (float64, error) { return a * b, nil },
-			"/": func(a, b float64) (float64, error) {
-				if b == 0 {
-					return 0, fmt.Errorf("деление на ноль")
-				}
-				return a / b, nil
-			},
-			"÷": func(a, b float64) (float64, error) {
-				if b == 0 {
-					return 0, fmt.Errorf("деление на ноль")
-				}
-				return a / b, nil
-			},
-			"^":  func(a, b float64) (float64, error) { return math.Pow(a, b), nil },
-			"**": func(a, b float64) (float64, error) { return math.Pow(a, b), nil },
-			"%": func(a, b float64) (float64, error) {
-				if b == 0 {
-					return 0, fmt.Errorf("деление на ноль при вычислении остатка")
-				}
-				return math.Mod(a, b), nil
-			},
+func NewCalculator(translator *i18n.Translator) *Calculator {
+	return &Calculator{translator: translator}
+}
+
+// operators builds the binary-op table used during RPN evaluation, with
+// error messages translated for lang.
+func (c *Calculator) operators(lang string) map[string]func(float64, float64) (float64, error) {
+	return map[string]func(float64, float64) (float64, error){
+		"+": func(a, b float64) (float64, error) { return a + b, nil },
+		"-": func(a, b float64) (float64, error) { return a - b, nil },
+		"*": func(a, b float64) (float64, error) { return a * b, nil },
+		"×": func(a, b float64) (float64, error) { return a * b, nil },
+		"/": func(a, b float64) (float64, error) {
+			if b == 0 {
+				return 0, c.translator.Errorf(lang, "div_by_zero")
+			}
+			return a / b, nil
+		},
+		"÷": func(a, b float64) (float64, error) {
+			if b == 0 {
+				return 0, c.translator.Errorf(lang, "div_by_zero")
+			}
+			return a / b, nil
+		},
+		"^":  func(a, b float64) (float64, error) { return math.Pow(a, b), nil },
+		"**": func(a, b float64) (float64, error) { return math.Pow(a, b), nil },
+		"%": func(a, b float64) (float64, error) {
+			if b == 0 {
+				return 0, c.translator.Errorf(lang, "div_by_zero_mod")
+			}
+			return math.Mod(a, b), nil
 		},
 	}
 }
 
-func (c *Calculator) validateExpression(expr string) error {
+func (c *Calculator) validateExpression(expr, lang string) error {
 	if len(expr) > maxExpressionLength {
-		return fmt.Errorf("выражение слишком длинное (максимум %d символов)", maxExpressionLength)
+		return c.translator.Errorf(lang, "expr_too_long", maxExpressionLength)
 	}
 
 	if strings.TrimSpace(expr) == "" {
-		return fmt.Errorf("пустое выражение")
+		return c.translator.Errorf(lang, "expr_empty")
 	}
 
-	validChars := regexp.MustCompile(`^[0-9+\-*/×÷^%().\s]+$`)
+	validChars := regexp.MustCompile(`^[0-9a-zA-Zа-яА-Я+\-*/×÷^%(),!.\s]+$`)
 	if !validChars.MatchString(expr) {
-		return fmt.Errorf("выражение содержит недопустимые символы")
+		return c.translator.Errorf(lang, "expr_invalid_chars")
 	}
 
 	return nil
 }
-func (c *Calculator) parseExpression(expr string) (float64, string, float64, error) {
-	expr = strings.ReplaceAll(expr, " ", "")
-
-	operators := []string{"**", "÷", "×", "^", "%", "/", "*", "+", "-"}
-
-	for _, op := range operators {
-
-		if op == "-" || op == "+" {
-			for i := 1; i < len(expr); i++ {
-				if string(expr[i]) == op {
-					prevChar := expr[i-1]
-					if prevChar >= '0' && prevChar <= '9' || prevChar == ')' {
-						left := expr[:i]
-						right := expr[i+1:]
-						if right != "" {
-							a, err1 := strconv.ParseFloat(left, 64)
-							b, err2 := strconv.ParseFloat(right, 64)
-							if err1 == nil && err2 == nil {
-								return a, op, b, nil
-							}
-						}
-					}
-				}
-			}
-		} else {
-			if idx := strings.Index(expr, op); idx > 0 {
-				left := expr[:idx]
-				right := expr[idx+len(op):]
-				if right != "" {
-					a, err1 := strconv.ParseFloat(left, 64)
-					b, err2 := strconv.ParseFloat(right, 64)
-					if err1 == nil && err2 == nil {
-						return a, op, b, nil
-					}
-				}
-			}
-		}
-	}
-
-	return 0, "", 0, fmt.Errorf("операция не найдена или неправильный формат")
-}
 
-func (c *Calculator) Calculate(expr string) (string, error) {
-	if err := c.validateExpression(expr); err != nil {
+func (c *Calculator) Calculate(input, lang string) (string, error) {
+	if err := c.validateExpression(input, lang); err != nil {
 		return "", err
 	}
 
-	a, op, b, err := c.parseExpression(expr)
+	tokens, err := expr.Tokenize(input)
 	if err != nil {
 		return "", err
 	}
 
-	opFunc, exists := c.supportedOps[op]
-	if !exists {
-		return "", fmt.Errorf("неподдерживаемая операция: %s", op)
+	rpn, err := expr.ToRPN(tokens)
+	if err != nil {
+		return "", err
 	}
 
-	result, err := opFunc(a, b)
+	result, err := expr.Eval(rpn, c.operators(lang))
 	if err != nil {
 		return "", err
 	}
 	if math.IsInf(result, 0) {
-		return "", fmt.Errorf("результат слишком велик")
+		return "", c.translator.Errorf(lang, "result_too_large")
 	}
 	if math.IsNaN(result) {
-		return "", fmt.Errorf("результат не является числом")
+		return "", c.translator.Errorf(lang, "result_nan")
 	}
 
 	if result == float64(int64(result)) {
@@ -146,131 +119,506 @@ func (c *Calculator) Calculate(expr string) (string, error) {
 	return fmt.Sprintf("%.6g", result), nil
 }
 
+// command is a single bot command: the description shown by Telegram's
+// command suggestion UI and the handler producing the reply text. args is
+// the text following the command name, e.g. "en" for "/lang en".
+type command struct {
+	description string
+	handler     func(message *tgbotapi.Message, args string) string
+}
+
+// inlineCacheTTL is how long Telegram is told to cache an inline query
+// result before asking the bot again.
+const inlineCacheTTL = 30
+
+// inlineRateLimit is the minimum interval between inline queries answered
+// for a single user, to guard against abuse.
+const inlineRateLimit = 1 * time.Second
+
+// rateLimiter tracks the last time each key was allowed through, rejecting
+// any key seen again before interval has elapsed.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	lastSeen map[int64]time.Time
+}
+
+func newRateLimiter(interval time.Duration) *rateLimiter {
+	return &rateLimiter{
+		interval: interval,
+		lastSeen: make(map[int64]time.Time),
+	}
+}
+
+func (r *rateLimiter) Allow(id int64) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if last, ok := r.lastSeen[id]; ok && now.Sub(last) < r.interval {
+		return false
+	}
+	r.lastSeen[id] = now
+	r.evictStale(now)
+	return true
+}
+
+// evictStale drops entries last seen more than interval ago, so lastSeen
+// only grows with users active within the current rate-limit window
+// instead of every distinct ID the process has ever observed.
+func (r *rateLimiter) evictStale(now time.Time) {
+	for id, last := range r.lastSeen {
+		if now.Sub(last) >= r.interval {
+			delete(r.lastSeen, id)
+		}
+	}
+}
+
 type BotHandler struct {
-	bot        *tgbotapi.BotAPI
-	calculator *Calculator
+	bot           *tgbotapi.BotAPI
+	calculator    *Calculator
+	history       *history.History
+	translator    *i18n.Translator
+	rates         convert.RateProvider
+	commands      map[string]command
+	inlineLimiter *rateLimiter
+	metrics       *metrics.Metrics
+}
+
+func NewBotHandler(bot *tgbotapi.BotAPI, h *history.History, translator *i18n.Translator, rates convert.RateProvider, m *metrics.Metrics) *BotHandler {
+	bh := &BotHandler{
+		bot:           bot,
+		calculator:    NewCalculator(translator),
+		history:       h,
+		translator:    translator,
+		rates:         rates,
+		inlineLimiter: newRateLimiter(inlineRateLimit),
+		metrics:       m,
+	}
+
+	bh.commands = map[string]command{
+		"/start":   {"Начало работы с ботом / Get started", bh.cmdStart},
+		"/help":    {"Справка / Help", bh.cmdHelp},
+		"/history": {"История вычислений / Calculation history", bh.cmdHistory},
+		"/clear":   {"Очистить историю / Clear history", bh.cmdClear},
+		"/lang":    {"Выбрать язык / Choose language", bh.cmdLang},
+		"/xr":      {"Курс валют / Currency exchange", bh.cmdExchange},
+		"/conv":    {"Перевод единиц / Unit conversion", bh.cmdConvert},
+	}
+
+	return bh
+}
+
+// lang returns chatID's saved language preference, falling back to
+// i18n.DefaultLang if none is set.
+func (h *BotHandler) lang(chatID int64) string {
+	if lang := h.history.Lang(chatID); lang != "" {
+		return lang
+	}
+	return i18n.DefaultLang
+}
+
+func (h *BotHandler) cmdStart(message *tgbotapi.Message, _ string) string {
+	return h.translator.T(h.lang(message.Chat.ID), "start_message")
+}
+
+func (h *BotHandler) cmdHelp(message *tgbotapi.Message, _ string) string {
+	return h.translator.T(h.lang(message.Chat.ID), "help_message")
+}
+
+func (h *BotHandler) cmdHistory(message *tgbotapi.Message, _ string) string {
+	lang := h.lang(message.Chat.ID)
+
+	entries := h.history.List(message.Chat.ID)
+	if len(entries) == 0 {
+		return h.translator.T(lang, "history_empty")
+	}
+
+	var b strings.Builder
+	for i, e := range entries {
+		fmt.Fprintf(&b, "%d) %s = %s\n", i+1, e.Expr, e.Result)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func (h *BotHandler) cmdClear(message *tgbotapi.Message, _ string) string {
+	lang := h.lang(message.Chat.ID)
+	if err := h.history.Clear(message.Chat.ID); err != nil {
+		slog.Error("failed to clear history", "chat_id", message.Chat.ID, "error", err)
+	}
+	return h.translator.T(lang, "history_cleared")
+}
+
+func (h *BotHandler) cmdLang(message *tgbotapi.Message, args string) string {
+	lang := h.lang(message.Chat.ID)
+
+	newLang := strings.ToLower(strings.TrimSpace(args))
+	if newLang == "" {
+		return h.translator.T(lang, "lang_usage")
+	}
+	if !h.translator.Supported(newLang) {
+		return h.translator.T(lang, "lang_unknown", newLang)
+	}
+
+	if err := h.history.SetLang(message.Chat.ID, newLang); err != nil {
+		slog.Error("failed to save language preference", "chat_id", message.Chat.ID, "error", err)
+	}
+	return h.translator.T(newLang, "lang_set", newLang)
 }
 
-func NewBotHandler(bot *tgbotapi.BotAPI) *BotHandler {
-	return &BotHandler{
-		bot:        bot,
-		calculator: NewCalculator(),
+// cmdExchange handles "/xr <amount> <from> to <to>", reusing the expression
+// engine to evaluate amount so it can itself be an expression (e.g. "2+3").
+func (h *BotHandler) cmdExchange(message *tgbotapi.Message, args string) string {
+	lang := h.lang(message.Chat.ID)
+
+	fields := strings.Fields(args)
+	if len(fields) != 4 || !strings.EqualFold(fields[2], "to") {
+		return h.translator.T(lang, "xr_usage")
 	}
+	from, to := strings.ToUpper(fields[1]), strings.ToUpper(fields[3])
+
+	amount, err := h.evalAmount(fields[0], lang)
+	if err != nil {
+		return h.translator.T(lang, "error_prefix", err.Error())
+	}
+
+	rates, err := h.rates.Get(from, to)
+	if err != nil {
+		return h.translator.T(lang, "error_prefix", err.Error())
+	}
+	fromRate, ok := rates[from]
+	if !ok {
+		return h.translator.T(lang, "error_prefix", h.translator.T(lang, "xr_unknown_currency", from))
+	}
+	toRate, ok := rates[to]
+	if !ok {
+		return h.translator.T(lang, "error_prefix", h.translator.T(lang, "xr_unknown_currency", to))
+	}
+
+	converted := amount * fromRate / toRate
+	return fmt.Sprintf("%s %s = %s %s", convert.FormatMoney(amount), from, convert.FormatMoney(converted), to)
 }
 
+// cmdConvert handles "/conv <amount> <from-unit> <to-unit>".
+func (h *BotHandler) cmdConvert(message *tgbotapi.Message, args string) string {
+	lang := h.lang(message.Chat.ID)
+
+	fields := strings.Fields(args)
+	if len(fields) != 3 {
+		return h.translator.T(lang, "conv_usage")
+	}
+
+	amount, err := h.evalAmount(fields[0], lang)
+	if err != nil {
+		return h.translator.T(lang, "error_prefix", err.Error())
+	}
+
+	fromCat, ok := convert.CategoryOf(fields[1])
+	if !ok {
+		return h.translator.T(lang, "error_prefix", h.translator.T(lang, "conv_unknown_unit", fields[1]))
+	}
+	toCat, ok := convert.CategoryOf(fields[2])
+	if !ok {
+		return h.translator.T(lang, "error_prefix", h.translator.T(lang, "conv_unknown_unit", fields[2]))
+	}
+	if fromCat != toCat {
+		return h.translator.T(lang, "error_prefix", h.translator.T(lang, "conv_mismatched_category", fields[1], fields[2]))
+	}
+
+	converted, err := convert.ConvertUnit(amount, fields[1], fields[2])
+	if err != nil {
+		return h.translator.T(lang, "error_prefix", err.Error())
+	}
+
+	return fmt.Sprintf("%.6g %s = %.6g %s", amount, fields[1], converted, fields[2])
+}
+
+// evalAmount evaluates an amount token through the calculator so it can be
+// a plain number or an arbitrary expression.
+func (h *BotHandler) evalAmount(token, lang string) (float64, error) {
+	result, err := h.calculator.Calculate(token, lang)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(result, 64)
+}
+
+func (h *BotHandler) calculate(message *tgbotapi.Message, text string) (string, bool) {
+	lang := h.lang(message.Chat.ID)
+
+	expr, err := h.history.Substitute(message.Chat.ID, text)
+	if err != nil {
+		return h.translator.T(lang, "error_prefix", err.Error()), false
+	}
+
+	result, err := h.calculator.Calculate(expr, lang)
+	if err != nil {
+		return h.translator.T(lang, "error_prefix", err.Error()), false
+	}
+
+	if err := h.history.Add(message.Chat.ID, text, result); err != nil {
+		slog.Error("failed to save history", "chat_id", message.Chat.ID, "error", err)
+	}
+	return h.translator.T(lang, "result_prefix") + result, true
+}
+
+// handleMessage dispatches an incoming message to a command or the
+// calculator, logging one structured event and recording metrics for the
+// outcome.
 func (h *BotHandler) handleMessage(message *tgbotapi.Message) {
 	if message == nil || message.Text == "" {
 		return
 	}
 
-	var reply string
+	start := time.Now()
 	text := strings.TrimSpace(message.Text)
 
-	switch {
-	case text == "/start":
-		reply = `Привет! Я калькулятор-бот.
-		
-Поддерживаемые операции:
-• Сложение: +
-• Вычитание: -
-• Умножение: * или ×
-• Деление: / или ÷
-• Возведение в степень: ^ или **
-• Остаток от деления: %
-
-Примеры:
-• 2 + 3
-• 10.5 * 2
-• 16 / 4
-• 2 ^ 3
-• 10 % 3
-
-Просто отправьте мне математическое выражение!`
-
-	case text == "/help":
-		reply = `Справка по использованию:
-
-Отправьте математическое выражение в формате: число операция число
-
-Примеры корректных выражений:
-• 15 + 25
-• 100 - 50
-• 12.5 * 4
-• 144 / 12
-• 2 ^ 10
-• 17 % 5
-
-Ограничения:
-• Максимум 100 символов
-• Только простые выражения (два числа и одна операция)
-• Деление на ноль запрещено`
-
-	default:
-		result, err := h.calculator.Calculate(text)
-		if err != nil {
-			reply = "Ошибка: " + err.Error() + "\n\nИспользуйте /help для получения справки."
+	name, args, _ := strings.Cut(text, " ")
+
+	var reply, outcome string
+	if cmd, ok := h.commands[name]; ok {
+		reply = cmd.handler(message, args)
+		outcome = "command"
+	} else {
+		var ok bool
+		reply, ok = h.calculate(message, text)
+		if ok {
+			outcome = "ok"
 		} else {
-			reply = "✅Результат: " + result
+			outcome = "error"
 		}
 	}
 
+	duration := time.Since(start)
+	h.metrics.RequestsTotal.WithLabelValues(outcome).Inc()
+	h.metrics.Duration.Observe(duration.Seconds())
+
+	var userID int64
+	if message.From != nil {
+		userID = message.From.ID
+	}
+	slog.Info("handled update",
+		"chat_id", message.Chat.ID,
+		"user_id", userID,
+		"expr_len", len(text),
+		"duration_ms", duration.Milliseconds(),
+		"outcome", outcome,
+	)
+
 	msg := tgbotapi.NewMessage(message.Chat.ID, reply)
 	msg.ReplyToMessageID = message.MessageID
 
 	if _, err := h.bot.Send(msg); err != nil {
-		log.Printf("Ошибка отправки сообщения: %v", err)
+		slog.Error("failed to send message", "chat_id", message.Chat.ID, "error", err)
+	}
+}
+
+// handleInlineQuery answers an inline query (e.g. "@yourbot 2+2" typed in
+// any chat) with a single article showing the computed result, logging one
+// structured event and recording metrics for the outcome.
+func (h *BotHandler) handleInlineQuery(iq *tgbotapi.InlineQuery) {
+	if iq == nil || iq.From == nil {
+		return
+	}
+	if !h.inlineLimiter.Allow(iq.From.ID) {
+		return
+	}
+
+	start := time.Now()
+	query := strings.TrimSpace(iq.Query)
+
+	lang := h.lang(iq.From.ID)
+
+	var article tgbotapi.InlineQueryResultArticle
+	var outcome string
+	switch {
+	case query == "":
+		article = tgbotapi.NewInlineQueryResultArticle(iq.ID, h.translator.T(lang, "inline_empty_title"), h.translator.T(lang, "inline_empty_description"))
+		outcome = "empty"
+	default:
+		result, err := h.calculator.Calculate(query, lang)
+		if err != nil {
+			article = tgbotapi.NewInlineQueryResultArticle(iq.ID, err.Error(), h.translator.T(lang, "error_prefix", err.Error()))
+			outcome = "error"
+		} else {
+			article = tgbotapi.NewInlineQueryResultArticle(iq.ID, query+" = "+result, "✅ "+query+" = "+result)
+			outcome = "ok"
+		}
+	}
+
+	duration := time.Since(start)
+	h.metrics.RequestsTotal.WithLabelValues(outcome).Inc()
+	h.metrics.Duration.Observe(duration.Seconds())
+
+	slog.Info("handled update",
+		"chat_id", int64(0),
+		"user_id", iq.From.ID,
+		"expr_len", len(query),
+		"duration_ms", duration.Milliseconds(),
+		"outcome", outcome,
+	)
+
+	answer := tgbotapi.InlineConfig{
+		InlineQueryID: iq.ID,
+		Results:       []interface{}{article},
+		CacheTime:     inlineCacheTTL,
+		IsPersonal:    true,
+	}
+
+	if _, err := h.bot.Request(answer); err != nil {
+		slog.Error("failed to answer inline query", "error", err)
 	}
 }
 
 func (h *BotHandler) Start(ctx context.Context) error {
 	u := tgbotapi.NewUpdate(0)
 	u.Timeout = 60
+	u.AllowedUpdates = []string{"message", "inline_query"}
 
 	updates := h.bot.GetUpdatesChan(u)
 
-	log.Println("🤖 Бот запущен и готов к работе!")
+	slog.Info("bot started")
 
 	for {
 		select {
 		case <-ctx.Done():
-			log.Println("📴 Получен сигнал остановки, завершаем работу...")
+			slog.Info("stop signal received, shutting down")
 			h.bot.StopReceivingUpdates()
 			return ctx.Err()
 
 		case update := <-updates:
+			h.metrics.UpdatesInFlight.Inc()
 			go func(upd tgbotapi.Update) {
+				defer h.metrics.UpdatesInFlight.Dec()
 				defer func() {
 					if r := recover(); r != nil {
-						log.Printf("Паника при обработке сообщения: %v", r)
+						slog.Error("panic while handling update", "panic", r)
 					}
 				}()
 
-				h.handleMessage(upd.Message)
+				switch {
+				case upd.Message != nil:
+					h.handleMessage(upd.Message)
+				case upd.InlineQuery != nil:
+					h.handleInlineQuery(upd.InlineQuery)
+				}
 			}(update)
 		}
 	}
 }
 
+// registerCommands publishes the bot's command set to Telegram's command
+// suggestion UI via SetMyCommands, using commands as the single source of
+// truth so the suggested set never drifts from what handleMessage accepts.
+func registerCommands(bot *tgbotapi.BotAPI, commands map[string]command) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	botCommands := make([]tgbotapi.BotCommand, 0, len(names))
+	for _, name := range names {
+		botCommands = append(botCommands, tgbotapi.BotCommand{
+			Command:     strings.TrimPrefix(name, "/"),
+			Description: commands[name].description,
+		})
+	}
+
+	if _, err := bot.Request(tgbotapi.NewSetMyCommands(botCommands...)); err != nil {
+		slog.Error("failed to register bot commands", "error", err)
+	}
+}
+
+// newLogHandler builds an slog.Handler from the LOG_FORMAT ("text"|"json",
+// default "text") and LOG_LEVEL ("debug"|"info"|"warn"|"error", default
+// "info") environment variables.
+func newLogHandler() slog.Handler {
+	var level slog.Level
+	switch strings.ToLower(os.Getenv("LOG_LEVEL")) {
+	case "debug":
+		level = slog.LevelDebug
+	case "warn":
+		level = slog.LevelWarn
+	case "error":
+		level = slog.LevelError
+	default:
+		level = slog.LevelInfo
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+	if strings.ToLower(os.Getenv("LOG_FORMAT")) == "json" {
+		return slog.NewJSONHandler(os.Stdout, opts)
+	}
+	return slog.NewTextHandler(os.Stdout, opts)
+}
+
+// newMetricsServer builds the HTTP server exposing /metrics and /healthz,
+// listening on addr (defaulting to ":9090").
+func newMetricsServer(addr string, reg *prometheus.Registry, bot *tgbotapi.BotAPI) *http.Server {
+	if addr == "" {
+		addr = ":9090"
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		if bot.Self.ID == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	return &http.Server{Addr: addr, Handler: mux}
+}
+
 func main() {
-	log.SetFlags(log.LstdFlags | log.Lshortfile)
+	slog.SetDefault(slog.New(newLogHandler()))
 
 	botToken := os.Getenv("TELEGRAM_BOT_TOKEN")
 	if botToken == "" {
 		botToken = "7566241176:AAHIsMArqeqDEM8LxDv-9Rvh5zPmQCxa2a4"
-		log.Println("⚠️  Используется токен по умолчанию. Рекомендуется установить TELEGRAM_BOT_TOKEN")
+		slog.Warn("using default bot token, set TELEGRAM_BOT_TOKEN")
 	}
 
 	bot, err := tgbotapi.NewBotAPI(botToken)
 	if err != nil {
-		log.Fatalf("Ошибка создания бота: %v", err)
+		slog.Error("failed to create bot", "error", err)
+		os.Exit(1)
 	}
 	if os.Getenv("DEBUG") == "true" {
 		bot.Debug = true
 	}
 
-	log.Printf("Авторизован как @%s", bot.Self.UserName)
+	slog.Info("authorized", "username", bot.Self.UserName)
+
+	hist, err := history.New(os.Getenv("HISTORY_PATH"))
+	if err != nil {
+		slog.Error("failed to initialize history", "error", err)
+		os.Exit(1)
+	}
+
+	translator, err := i18n.New()
+	if err != nil {
+		slog.Error("failed to load locales", "error", err)
+		os.Exit(1)
+	}
+
+	rates := convert.NewCachingRateProvider(convert.NewHTTPRateProvider(), convert.DefaultCacheTTL)
+
+	reg := prometheus.NewRegistry()
+	m := metrics.New(reg)
+
+	handler := NewBotHandler(bot, hist, translator, rates, m)
+	registerCommands(bot, handler.commands)
 
-	handler := NewBotHandler(bot)
+	metricsServer := newMetricsServer(os.Getenv("METRICS_ADDR"), reg, bot)
+	go func() {
+		if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			slog.Error("metrics server failed", "error", err)
+		}
+	}()
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -279,14 +627,20 @@ func main() {
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 	go func() {
 		if err := handler.Start(ctx); err != nil && err != context.Canceled {
-			log.Printf("Ошибка работы бота: %v", err)
+			slog.Error("bot stopped with error", "error", err)
 		}
 	}()
 
 	<-sigChan
-	log.Println("Получен сигнал завершения...")
+	slog.Info("shutdown signal received")
 	cancel()
 
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer shutdownCancel()
+	if err := metricsServer.Shutdown(shutdownCtx); err != nil {
+		slog.Error("metrics server shutdown error", "error", err)
+	}
+
 	time.Sleep(2 * time.Second)
-	log.Println("Бот остановлен")
+	slog.Info("bot stopped")
 }
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/MederX/calculator-telegram-bot/internal/history"
+	"github.com/MederX/calculator-telegram-bot/internal/i18n"
+	"github.com/MederX/calculator-telegram-bot/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type fakeRates struct {
+	rates map[string]float64
+}
+
+func (f *fakeRates) Get(codes ...string) (map[string]float64, error) {
+	out := make(map[string]float64, len(codes))
+	for _, code := range codes {
+		out[code] = f.rates[code]
+	}
+	return out, nil
+}
+
+func newTestHandler(t *testing.T) *BotHandler {
+	t.Helper()
+
+	hist, err := history.New("")
+	if err != nil {
+		t.Fatalf("history.New() error = %v", err)
+	}
+	translator, err := i18n.New()
+	if err != nil {
+		t.Fatalf("i18n.New() error = %v", err)
+	}
+
+	rates := &fakeRates{rates: map[string]float64{"USD": 1, "EUR": 1.1}}
+	m := metrics.New(prometheus.NewRegistry())
+	return NewBotHandler(nil, hist, translator, rates, m)
+}
+
+func TestCmdExchange(t *testing.T) {
+	h := newTestHandler(t)
+	message := &tgbotapi.Message{Chat: &tgbotapi.Chat{ID: 1}}
+
+	reply := h.cmdExchange(message, "100 USD to EUR")
+	if !strings.Contains(reply, "EUR") || !strings.Contains(reply, "USD") {
+		t.Errorf("cmdExchange() = %q, want both currency codes present", reply)
+	}
+
+	if reply := h.cmdExchange(message, "not enough args"); !strings.Contains(reply, "/xr") {
+		t.Errorf("cmdExchange() with bad args = %q, want usage message", reply)
+	}
+}
+
+func TestCmdConvert(t *testing.T) {
+	h := newTestHandler(t)
+	message := &tgbotapi.Message{Chat: &tgbotapi.Chat{ID: 1}}
+
+	reply := h.cmdConvert(message, "1 km m")
+	if !strings.Contains(reply, "1000") {
+		t.Errorf("cmdConvert(1 km m) = %q, want result to contain 1000", reply)
+	}
+
+	if reply := h.cmdConvert(message, "1 kg m"); !strings.Contains(reply, "Ошибка") {
+		t.Errorf("cmdConvert(1 kg m) = %q, want error for mismatched units", reply)
+	}
+}
+
+func TestRateLimiterEvictsStaleEntries(t *testing.T) {
+	r := newRateLimiter(time.Minute)
+
+	if !r.Allow(1) {
+		t.Fatal("Allow(1) first call = false, want true")
+	}
+	if r.Allow(1) {
+		t.Error("Allow(1) second call within interval = true, want false")
+	}
+
+	r.evictStale(time.Now().Add(2 * time.Minute))
+	if len(r.lastSeen) != 0 {
+		t.Errorf("lastSeen has %d entries after evicting, want 0", len(r.lastSeen))
+	}
+}